@@ -0,0 +1,94 @@
+package rndstring
+
+import "math"
+
+// An EntropyReporter is an optional sidecar interface a StringGenerator
+// may implement to report the Shannon entropy, in bits, of the strings
+// it produces. Not every StringGenerator satisfies it; use EntropyBits
+// to query one without a type assertion.
+type EntropyReporter interface {
+	// EntropyBits returns the Shannon entropy, in bits, of a single
+	// generated string.
+	EntropyBits() float64
+}
+
+// EntropyBits returns the Shannon entropy, in bits, that g's output
+// carries, and whether g reports one at all. Callers can use this to
+// programmatically enforce a minimum strength (e.g. reject any
+// configured generator under 80 bits) instead of guessing from string
+// length.
+func EntropyBits(g StringGenerator) (float64, bool) {
+	er, ok := g.(EntropyReporter)
+
+	if !ok {
+		return 0, false
+	}
+
+	return er.EntropyBits(), true
+}
+
+// entropyGenerator wraps a generator with a precomputed entropy value,
+// satisfying both StringGenerator (via the embedded generator) and
+// EntropyReporter.
+type entropyGenerator struct {
+	generator
+	bits float64
+}
+
+func (e entropyGenerator) EntropyBits() float64 {
+	return e.bits
+}
+
+// runeEntropyBits returns the Shannon entropy, in bits, of N characters
+// drawn uniformly at random from alphabet's indices. Unlike a flat
+// N*log2(len(alphabet)) it accounts for repeated runes (each index is
+// equally likely, but a rune appearing k times is k times as likely as
+// one appearing once), which is what NewAlphabetGenerator needs since it
+// doesn't require a caller-supplied alphabet to be deduplicated.
+func runeEntropyBits(N int, alphabet []rune) float64 {
+	if N <= 0 || len(alphabet) == 0 {
+		return 0
+	}
+
+	counts := make(map[rune]int, len(alphabet))
+	for _, r := range alphabet {
+		counts[r]++
+	}
+
+	L := float64(len(alphabet))
+	perChar := 0.0
+
+	for _, c := range counts {
+		p := float64(c) / L
+		perChar -= p * math.Log2(p)
+	}
+
+	return float64(N) * perChar
+}
+
+// selectNFromEntropyBits returns the Shannon entropy, in bits, of N
+// characters produced by selectNFrom from the given alphabets.
+// selectNFrom picks one of the alphabets uniformly at random and then a
+// character uniformly within it, so unlike a flat uniform draw over the
+// concatenation of all alphabets, unequal alphabet sizes make characters
+// from the smaller alphabets individually more likely.
+func selectNFromEntropyBits(N int, alphabets [][]byte) float64 {
+	if N <= 0 || len(alphabets) == 0 {
+		return 0
+	}
+
+	k := float64(len(alphabets))
+	perChar := 0.0
+
+	for _, a := range alphabets {
+		if len(a) == 0 {
+			continue
+		}
+
+		perChar += math.Log2(k * float64(len(a)))
+	}
+
+	perChar /= k
+
+	return float64(N) * perChar
+}
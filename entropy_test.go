@@ -0,0 +1,99 @@
+package rndstring
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEntropyBitsHexGenerator(t *testing.T) {
+	g, err := NewHexGenerator(16)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	bits, ok := EntropyBits(g)
+
+	if !ok {
+		t.Fatalf("expected hex generator to report entropy")
+	}
+
+	if bits != 128 {
+		t.Errorf("expected 128 bits, got %f", bits)
+	}
+}
+
+func TestEntropyBitsAlphabetGenerator(t *testing.T) {
+	g, err := NewAlphabetGenerator(10, []rune("abcd"))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	bits, ok := EntropyBits(g)
+
+	if !ok {
+		t.Fatalf("expected alphabet generator to report entropy")
+	}
+
+	want := 10 * math.Log2(4)
+
+	if math.Abs(bits-want) > 1e-9 {
+		t.Errorf("expected %f bits, got %f", want, bits)
+	}
+}
+
+func TestEntropyBitsAlphabetGeneratorWithDuplicates(t *testing.T) {
+	g, err := NewAlphabetGenerator(1, []rune("aab"))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	bits, ok := EntropyBits(g)
+
+	if !ok {
+		t.Fatalf("expected alphabet generator to report entropy")
+	}
+
+	// 'a' has probability 2/3, 'b' has probability 1/3.
+	want := -(2.0/3.0*math.Log2(2.0/3.0) + 1.0/3.0*math.Log2(1.0/3.0))
+
+	if math.Abs(bits-want) > 1e-9 {
+		t.Errorf("expected %f bits, got %f", want, bits)
+	}
+}
+
+func TestEntropyBitsMultiAlphabetGenerator(t *testing.T) {
+	g, err := NewLowerCaseDigitsGenerator(1)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	bits, ok := EntropyBits(g)
+
+	if !ok {
+		t.Fatalf("expected generator to report entropy")
+	}
+
+	// Each alphabet (26 letters, 10 digits) is chosen with probability
+	// 1/2, then a character within it uniformly.
+	want := 0.5*math.Log2(2*26) + 0.5*math.Log2(2*10)
+
+	if math.Abs(bits-want) > 1e-9 {
+		t.Errorf("expected %f bits, got %f", want, bits)
+	}
+}
+
+func TestEntropyBitsUnsupportedGenerator(t *testing.T) {
+	g, err := NewDummyGenerator(10)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, ok := EntropyBits(g); ok {
+		t.Errorf("expected dummy generator not to report entropy")
+	}
+}
@@ -0,0 +1,129 @@
+package rndstring
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"os/user"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/chacha20"
+)
+
+// OnFallback, when set, is invoked once crypto/rand has failed and
+// ReadBytes is about to fall back to the userspace DRBG below. Operators
+// can use it to alert that crypto/rand is unavailable on this host.
+var OnFallback func(error)
+
+var fallbackWarnOnce sync.Once
+
+func reportFallback(err error) {
+	fallbackWarnOnce.Do(func() {
+		if OnFallback != nil {
+			OnFallback(err)
+		}
+	})
+}
+
+// A fallbackDRBG is a ChaCha20-keystream based CSPRNG, used only when
+// crypto/rand itself is unavailable. It is reseeded periodically by
+// mixing its own keystream with fresh best-effort entropy, so a single
+// weak seed at startup doesn't compromise all of its future output.
+type fallbackDRBG struct {
+	mu         sync.Mutex
+	key        [32]byte
+	cipher     *chacha20.Cipher
+	bytesSince int
+	lastReseed time.Time
+}
+
+const (
+	fallbackReseedBytes = 1 << 20 // reseed after this many bytes of output
+	fallbackReseedEvery = 10 * time.Minute
+)
+
+var fallback = newFallbackDRBG()
+
+func newFallbackDRBG() *fallbackDRBG {
+	d := &fallbackDRBG{}
+	d.reseedLocked(gatherEntropy())
+	return d
+}
+
+// gatherEntropy collects a best-effort entropy sample from process and OS
+// state. None of these sources are individually trustworthy, but mixed
+// together through SHA-256 they make the fallback DRBG's seed far harder
+// to predict than a bare timestamp.
+func gatherEntropy() []byte {
+	h := sha256.New()
+
+	fmt.Fprintf(h, "%d", time.Now().UnixNano())
+	fmt.Fprintf(h, "%d", os.Getpid())
+
+	var m1, m2 runtime.MemStats
+	runtime.ReadMemStats(&m1)
+	runtime.GC()
+	runtime.ReadMemStats(&m2)
+	fmt.Fprintf(h, "%+v%+v", m1, m2)
+
+	if u, err := user.Current(); err == nil {
+		fmt.Fprintf(h, "%s:%s", u.Uid, u.Username)
+	}
+
+	h.Write([]byte(strings.Join(os.Args, "\x00")))
+	h.Write([]byte(strings.Join(os.Environ(), "\x00")))
+	h.Write(osEntropy())
+
+	return h.Sum(nil)
+}
+
+// reseedLocked mixes fresh entropy into the DRBG's key. The caller must
+// hold d.mu.
+func (d *fallbackDRBG) reseedLocked(entropy []byte) {
+	mix := sha256.New()
+	mix.Write(d.key[:])
+
+	if d.cipher != nil {
+		var ks [32]byte
+		d.cipher.XORKeyStream(ks[:], ks[:])
+		mix.Write(ks[:])
+	}
+
+	mix.Write(entropy)
+	newKey := mix.Sum(nil)
+	copy(d.key[:], newKey)
+
+	nonceSeed := sha256.Sum256(append(newKey, entropy...))
+
+	c, err := chacha20.NewUnauthenticatedCipher(d.key[:], nonceSeed[:chacha20.NonceSize])
+
+	if err != nil {
+		// Can't happen: key and nonce are always sized correctly above.
+		panic("rndstring: fallback DRBG: " + err.Error())
+	}
+
+	d.cipher = c
+	d.bytesSince = 0
+	d.lastReseed = time.Now()
+}
+
+func (d *fallbackDRBG) read(buf []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.bytesSince >= fallbackReseedBytes || time.Since(d.lastReseed) >= fallbackReseedEvery {
+		d.reseedLocked(gatherEntropy())
+	}
+
+	d.cipher.XORKeyStream(buf, buf)
+	d.bytesSince += len(buf)
+}
+
+// ReadBytesFallback reads len(buf) random bytes using the
+// fall-back method.
+func ReadBytesFallback(buf []byte) {
+	fallback.read(buf)
+}
@@ -0,0 +1,38 @@
+package rndstring
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestReadBytesFallbackNonZero(t *testing.T) {
+	buf := make([]byte, 64)
+	ReadBytesFallback(buf)
+
+	allZero := true
+	for _, b := range buf {
+		if b != 0 {
+			allZero = false
+			break
+		}
+	}
+
+	if allZero {
+		t.Errorf("expected non-zero output from fallback DRBG")
+	}
+}
+
+func TestReportFallbackCallsOnFallbackOnce(t *testing.T) {
+	old := OnFallback
+	defer func() { OnFallback = old; fallbackWarnOnce = sync.Once{} }()
+
+	calls := 0
+	OnFallback = func(error) { calls++ }
+
+	reportFallback(nil)
+	reportFallback(nil)
+
+	if calls != 1 {
+		t.Errorf("expected OnFallback to be called exactly once, got %d", calls)
+	}
+}
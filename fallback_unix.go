@@ -0,0 +1,30 @@
+//go:build !windows
+
+package rndstring
+
+import (
+	"io"
+	"os"
+)
+
+// osEntropy reads a best-effort entropy sample from /dev/urandom (the
+// portable interface to the kernel's getrandom(2) pool). Errors, including
+// a short read, are treated as "no extra entropy available": gatherEntropy
+// already mixes in several other sources, and this one is pure bonus on a
+// host where crypto/rand has already failed.
+func osEntropy() []byte {
+	f, err := os.Open("/dev/urandom")
+
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	buf := make([]byte, 32)
+
+	if _, err := io.ReadFull(f, buf); err != nil {
+		return nil
+	}
+
+	return buf
+}
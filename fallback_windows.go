@@ -0,0 +1,9 @@
+//go:build windows
+
+package rndstring
+
+// osEntropy has no extra OS-level source to draw from on Windows;
+// gatherEntropy's other sources still apply.
+func osEntropy() []byte {
+	return nil
+}
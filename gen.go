@@ -1,9 +1,6 @@
 package rndstring
 
 import crand "crypto/rand"
-import mrand "math/rand"
-import "sync"
-import "time"
 import "encoding/hex"
 import "encoding/base64"
 import "encoding/base32"
@@ -48,40 +45,52 @@ func (g generator) Generate() string {
 // N bytes hex encoded. (Thus the size of the
 // returned string string is N*2).
 func NewHexGenerator(N int) (StringGenerator, error) {
-	return generator(func() string {
-		b := RandomBytes(N)
-		return hex.EncodeToString(b)
-	}), nil
+	return entropyGenerator{
+		generator: func() string {
+			b := RandomBytes(N)
+			return hex.EncodeToString(b)
+		},
+		bits: float64(N) * 8,
+	}, nil
 }
 
 // NewBase64Generator returns a new string generator returning
 // N bytes base64 encoded. (Thus the size of the
 // returned string string is longer than N).
 func NewBase64Generator(N int) (StringGenerator, error) {
-	return generator(func() string {
-		b := RandomBytes(N)
-		return base64.StdEncoding.EncodeToString(b)
-	}), nil
+	return entropyGenerator{
+		generator: func() string {
+			b := RandomBytes(N)
+			return base64.StdEncoding.EncodeToString(b)
+		},
+		bits: float64(N) * 8,
+	}, nil
 }
 
 // NewBase64URLGenerator returns a new string generator returning
 // N byte base64-url encoded WITHOUT padding. (Thus the size of the
 // returned string string is longer than N).
 func NewBase64URLGenerator(N int) (StringGenerator, error) {
-	return generator(func() string {
-		b := RandomBytes(N)
-		return base64.RawURLEncoding.EncodeToString(b)
-	}), nil
+	return entropyGenerator{
+		generator: func() string {
+			b := RandomBytes(N)
+			return base64.RawURLEncoding.EncodeToString(b)
+		},
+		bits: float64(N) * 8,
+	}, nil
 }
 
 // NewBase32Generator returns a new string generator returning
 // N bytes base32 encoded. (Thus the size of the
 // returned string string is longer than N).
 func NewBase32Generator(N int) (StringGenerator, error) {
-	return generator(func() string {
-		b := RandomBytes(N)
-		return base32.StdEncoding.EncodeToString(b)
-	}), nil
+	return entropyGenerator{
+		generator: func() string {
+			b := RandomBytes(N)
+			return base32.StdEncoding.EncodeToString(b)
+		},
+		bits: float64(N) * 8,
+	}, nil
 }
 
 // NewDummyGenerator returns a new string generator returning strings
@@ -100,6 +109,42 @@ var digitsAlphabet []byte = []byte("0123456789")
 // this doesn't contain '"` because they are easily confused.
 var symbolsAlphabet []byte = []byte("+-*/@&^%|$#!?[]{}()\\:,.;=")
 
+// uniformIndex returns a uniformly distributed index in [0, L) without
+// modulo bias, for any L > 0. It draws as many bytes as needed to cover
+// L (a single byte for the common L <= 256 case, more for larger L such
+// as a big Policy.ExtraAlphabet), and rejects any draw that would make
+// `draw % L` favour the low end of the range, retrying until one falls
+// inside the largest multiple of L that fits in that many bytes.
+func uniformIndex(L int) int {
+	if L <= 1 {
+		return 0
+	}
+
+	nbytes := 1
+	span := 256
+
+	for span < L {
+		nbytes++
+		span *= 256
+	}
+
+	max := span - (span % L)
+	buf := make([]byte, nbytes)
+
+	for {
+		ReadBytes(buf)
+
+		v := 0
+		for _, b := range buf {
+			v = v<<8 | int(b)
+		}
+
+		if v < max {
+			return v % L
+		}
+	}
+}
+
 // be aware there can at most be 255 alphabets and
 // an alphabet must not be longer than 255.
 func selectNFrom(N int, alphabets [][]byte) []byte {
@@ -107,17 +152,11 @@ func selectNFrom(N int, alphabets [][]byte) []byte {
 		return []byte{}
 	}
 
-	a := make([]byte, N)
-	j := make([]byte, N)
 	t := make([]byte, N)
 
-	ReadBytes(a)
-	ReadBytes(j)
-
 	for i := 0; i < N; i++ {
-		a_ := a[i] % byte(len(alphabets))
-		j_ := j[i] % byte(len(alphabets[a_]))
-		t[i] = alphabets[a_][j_]
+		a := alphabets[uniformIndex(len(alphabets))]
+		t[i] = a[uniformIndex(len(a))]
 	}
 
 	return t
@@ -158,89 +197,133 @@ func RandomIPv4() string {
 // NewLowerCaseGenerator returns a new string generator returning strings
 // of length N consisting of lower case letters.
 func NewLowerCaseGenerator(N int) (StringGenerator, error) {
-	return generator(func() string {
-		return string(selectNFrom(N, [][]byte{lowerCaseAlphabet}))
-	}), nil
+	alphabets := [][]byte{lowerCaseAlphabet}
+	return entropyGenerator{
+		generator: func() string {
+			return string(selectNFrom(N, alphabets))
+		},
+		bits: selectNFromEntropyBits(N, alphabets),
+	}, nil
 }
 
 // NewUpperCaseGenerator returns a new string generator returning strings
 // of length N consisting of upper case letters.
 func NewUpperCaseGenerator(N int) (StringGenerator, error) {
-	return generator(func() string {
-		return string(selectNFrom(N, [][]byte{upperCaseAlphabet}))
-	}), nil
+	alphabets := [][]byte{upperCaseAlphabet}
+	return entropyGenerator{
+		generator: func() string {
+			return string(selectNFrom(N, alphabets))
+		},
+		bits: selectNFromEntropyBits(N, alphabets),
+	}, nil
 }
 
 // NewDigitsGenerator returns a new string generator returning strings
 // of length N consisting of digits.
 func NewDigitsGenerator(N int) (StringGenerator, error) {
-	return generator(func() string {
-		return string(selectNFrom(N, [][]byte{digitsAlphabet}))
-	}), nil
+	alphabets := [][]byte{digitsAlphabet}
+	return entropyGenerator{
+		generator: func() string {
+			return string(selectNFrom(N, alphabets))
+		},
+		bits: selectNFromEntropyBits(N, alphabets),
+	}, nil
 }
 
 // NewSymbolsGenerator returns a new string generator returning strings
 // of length N consisting of symbols.
 func NewSymbolsGenerator(N int) (StringGenerator, error) {
-	return generator(func() string {
-		return string(selectNFrom(N, [][]byte{symbolsAlphabet}))
-	}), nil
+	alphabets := [][]byte{symbolsAlphabet}
+	return entropyGenerator{
+		generator: func() string {
+			return string(selectNFrom(N, alphabets))
+		},
+		bits: selectNFromEntropyBits(N, alphabets),
+	}, nil
 }
 
 // NewLowerCaseDigitsGenerator returns a new string generator returning strings
 // of length N consisting of lower case letters and digits.
 func NewLowerCaseDigitsGenerator(N int) (StringGenerator, error) {
-	return generator(func() string {
-		return string(selectNFrom(N, [][]byte{lowerCaseAlphabet, digitsAlphabet}))
-	}), nil
+	alphabets := [][]byte{lowerCaseAlphabet, digitsAlphabet}
+	return entropyGenerator{
+		generator: func() string {
+			return string(selectNFrom(N, alphabets))
+		},
+		bits: selectNFromEntropyBits(N, alphabets),
+	}, nil
 }
 
 // NewUpperCaseDigitsGenerator returns a new string generator returning strings
 // of length N consisting of upper case letters and digits.
 func NewUpperCaseDigitsGenerator(N int) (StringGenerator, error) {
-	return generator(func() string {
-		return string(selectNFrom(N, [][]byte{upperCaseAlphabet, digitsAlphabet}))
-	}), nil
+	alphabets := [][]byte{upperCaseAlphabet, digitsAlphabet}
+	return entropyGenerator{
+		generator: func() string {
+			return string(selectNFrom(N, alphabets))
+		},
+		bits: selectNFromEntropyBits(N, alphabets),
+	}, nil
 }
 
 // NewLowerCaseSymbolsGenerator returns a new string generator returning strings
 // of length N consisting of lower case letters and symbols.
 func NewLowerCaseSymbolsGenerator(N int) (StringGenerator, error) {
-	return generator(func() string {
-		return string(selectNFrom(N, [][]byte{lowerCaseAlphabet, symbolsAlphabet}))
-	}), nil
+	alphabets := [][]byte{lowerCaseAlphabet, symbolsAlphabet}
+	return entropyGenerator{
+		generator: func() string {
+			return string(selectNFrom(N, alphabets))
+		},
+		bits: selectNFromEntropyBits(N, alphabets),
+	}, nil
 }
 
 // NewLettersGenerator returns a new string generator returning strings
 // of length N consisting of letters.
 func NewLettersGenerator(N int) (StringGenerator, error) {
-	return generator(func() string {
-		return string(selectNFrom(N, [][]byte{lowerCaseAlphabet, upperCaseAlphabet}))
-	}), nil
+	alphabets := [][]byte{lowerCaseAlphabet, upperCaseAlphabet}
+	return entropyGenerator{
+		generator: func() string {
+			return string(selectNFrom(N, alphabets))
+		},
+		bits: selectNFromEntropyBits(N, alphabets),
+	}, nil
 }
 
 // NewLettersDigitsGenerator returns a new string generator returning strings
 // of length N consisting of letters and digits.
 func NewLettersDigitsGenerator(N int) (StringGenerator, error) {
-	return generator(func() string {
-		return string(selectNFrom(N, [][]byte{lowerCaseAlphabet, upperCaseAlphabet, digitsAlphabet}))
-	}), nil
+	alphabets := [][]byte{lowerCaseAlphabet, upperCaseAlphabet, digitsAlphabet}
+	return entropyGenerator{
+		generator: func() string {
+			return string(selectNFrom(N, alphabets))
+		},
+		bits: selectNFromEntropyBits(N, alphabets),
+	}, nil
 }
 
 // NewLettersSymbolsGenerator returns a new string generator returning strings
 // of length N consisting of letters and symbols.
 func NewLettersSymbolsGenerator(N int) (StringGenerator, error) {
-	return generator(func() string {
-		return string(selectNFrom(N, [][]byte{lowerCaseAlphabet, upperCaseAlphabet, symbolsAlphabet}))
-	}), nil
+	alphabets := [][]byte{lowerCaseAlphabet, upperCaseAlphabet, symbolsAlphabet}
+	return entropyGenerator{
+		generator: func() string {
+			return string(selectNFrom(N, alphabets))
+		},
+		bits: selectNFromEntropyBits(N, alphabets),
+	}, nil
 }
 
 // NewLettersSymbolsDigitsGenerator returns a new string generator returning strings
 // of length N consisting of letters, symbols and digits.
 func NewLettersSymbolsDigitsGenerator(N int) (StringGenerator, error) {
-	return generator(func() string {
-		return string(selectNFrom(N, [][]byte{lowerCaseAlphabet, upperCaseAlphabet, digitsAlphabet, symbolsAlphabet}))
-	}), nil
+	alphabets := [][]byte{lowerCaseAlphabet, upperCaseAlphabet, digitsAlphabet, symbolsAlphabet}
+	return entropyGenerator{
+		generator: func() string {
+			return string(selectNFrom(N, alphabets))
+		},
+		bits: selectNFromEntropyBits(N, alphabets),
+	}, nil
 }
 
 // NewASCIIGenerator is just an alias for NewLettersSymbolsDigitsGenerator
@@ -253,9 +336,13 @@ var hexStrAlphabet []byte = []byte("0123456789abcdef")
 // NewHexStrGenerator returns a new string generator returning strings
 // of exactly length N consisting of 0-9 and a-f.
 func NewHexStrGenerator(N int) (StringGenerator, error) {
-	return generator(func() string {
-		return string(selectNFrom(N, [][]byte{hexStrAlphabet}))
-	}), nil
+	alphabets := [][]byte{hexStrAlphabet}
+	return entropyGenerator{
+		generator: func() string {
+			return string(selectNFrom(N, alphabets))
+		},
+		bits: selectNFromEntropyBits(N, alphabets),
+	}, nil
 }
 
 // NewAlphabetGenerator returns a new string generator returning strings
@@ -271,24 +358,22 @@ func NewAlphabetGenerator(N int, alphabet []rune) (StringGenerator, error) {
 	alphabet_ := make([]rune, len(alphabet))
 	copy(alphabet_, alphabet)
 
-	return generator(func() string {
-		if N < 0 {
-			return ""
-		}
-
-		indexes := make([]byte, N)
-		runes := make([]rune, N)
-		ReadBytes(indexes)
+	return entropyGenerator{
+		generator: func() string {
+			if N < 0 {
+				return ""
+			}
 
-		alphabetSize := byte(len(alphabet_))
+			runes := make([]rune, N)
 
-		for i := 0; i < N; i++ {
-			index := indexes[i] % alphabetSize
-			runes[i] = alphabet_[index]
-		}
+			for i := 0; i < N; i++ {
+				runes[i] = alphabet_[uniformIndex(len(alphabet_))]
+			}
 
-		return string(runes)
-	}), nil
+			return string(runes)
+		},
+		bits: runeEntropyBits(N, alphabet_),
+	}, nil
 }
 
 // StringGenerators returns the names of all available string generators.
@@ -321,6 +406,9 @@ var stringGenerators map[string]NewStringGeneratorF = map[string]NewStringGenera
 	"letters&symbols&digits": NewLettersSymbolsDigitsGenerator,
 	"ascii":                  NewLettersSymbolsDigitsGenerator,
 	"hexstr":                 NewHexStrGenerator,
+	"policy":                 newPolicyGenerator,
+	"pronounceable":          NewPronounceableGenerator,
+	"passphrase":             newDicewareGenerator,
 }
 
 // Join joins the strings generated by the generators together using the
@@ -350,7 +438,8 @@ func RegisterStringGenerator(name string, f NewStringGeneratorF) {
 // NewStringGenerator returns a new StringGenerator by name and length. Length may
 // either refer to the total length of the string or the amount
 // of bytes it encodes (this is for example the case when using base32,
-// base64 or hex).
+// base64 or hex). For "passphrase" it is the number of words, not a
+// byte or character count; see NewDicewareGenerator.
 func NewStringGenerator(name string, N int) (StringGenerator, error) {
 	fn := stringGenerators[name]
 
@@ -381,6 +470,7 @@ func ReadBytes(buf []byte) {
 	_, err := crand.Read(buf)
 
 	if err != nil {
+		reportFallback(err)
 		ReadBytesFallback(buf)
 	}
 }
@@ -394,38 +484,3 @@ func ReadBytesNoFallback(buf []byte) {
 		panic(err.Error())
 	}
 }
-
-var source mrand.Source = mrand.NewSource(time.Now().UnixNano())
-var rnd *mrand.Rand = mrand.New(source)
-var mutex *sync.Mutex = &sync.Mutex{}
-var skipBuf = make([]byte, 13)
-
-// skip a "random" number of bytes
-func skip() {
-	now := time.Now().UnixNano() % 32
-
-	for i := int64(0); i < now; i++ {
-		rnd.Read(skipBuf)
-	}
-}
-
-// ReadBytesFallback reads len(buf) random bytes using the
-// fall-back method.
-func ReadBytesFallback(buf []byte) {
-	mutex.Lock()
-
-	skip() // skip some bytes
-
-	rnd.Read(buf) //mrand always returns len(buf), nil
-	sbuf := make([]byte, len(buf))
-	rnd.Read(sbuf)
-
-	now := byte(time.Now().UnixNano() % 256)
-
-	for i := 0; i < len(buf); i++ {
-		buf[i] ^= sbuf[i]
-		buf[i] ^= now
-	}
-
-	mutex.Unlock()
-}
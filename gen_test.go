@@ -0,0 +1,83 @@
+package rndstring
+
+import "testing"
+
+// chiSquare computes the chi-square statistic for observed counts against
+// a uniform distribution over len(counts) categories.
+func chiSquare(counts []int, samples int) float64 {
+	expected := float64(samples) / float64(len(counts))
+	sum := 0.0
+
+	for _, c := range counts {
+		d := float64(c) - expected
+		sum += d * d / expected
+	}
+
+	return sum
+}
+
+// TestSelectNFromUnbiased checks that picking from a 25-symbol alphabet
+// (which does not divide 256 evenly, and would be visibly biased under a
+// naive modulo) is close enough to uniform to pass a chi-square test.
+func TestSelectNFromUnbiased(t *testing.T) {
+	alphabet := []byte("ABCDEFGHIJKLMNOPQRSTUVWXY") // 25 symbols
+	const samples = 250000
+
+	out := selectNFrom(samples, [][]byte{alphabet})
+
+	counts := make([]int, len(alphabet))
+	index := make(map[byte]int, len(alphabet))
+	for i, b := range alphabet {
+		index[b] = i
+	}
+
+	for _, b := range out {
+		counts[index[b]]++
+	}
+
+	stat := chiSquare(counts, samples)
+
+	// 24 degrees of freedom; the 0.001 critical value is ~51.18. A
+	// tighter alpha than the usual 0.01 keeps this test (and its sibling
+	// below) from spuriously failing a perfectly unbiased generator.
+	const critical = 51.18
+
+	if stat > critical {
+		t.Errorf("chi-square statistic %.2f exceeds critical value %.2f, distribution looks biased", stat, critical)
+	}
+}
+
+// TestAlphabetGeneratorUnbiased runs the same chi-square check through
+// NewAlphabetGenerator to cover its independent code path.
+func TestAlphabetGeneratorUnbiased(t *testing.T) {
+	alphabet := []rune("ABCDEFGHIJKLMNOPQRSTUVWXY") // 25 symbols
+	const samples = 250000
+
+	g, err := NewAlphabetGenerator(samples, alphabet)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	out := []rune(g.Generate())
+
+	counts := make([]int, len(alphabet))
+	index := make(map[rune]int, len(alphabet))
+	for i, r := range alphabet {
+		index[r] = i
+	}
+
+	for _, r := range out {
+		counts[index[r]]++
+	}
+
+	stat := chiSquare(counts, samples)
+
+	// See TestSelectNFromUnbiased for why this uses the 0.001 critical
+	// value instead of the usual 0.01.
+	const critical = 51.18
+
+	if stat > critical {
+		t.Errorf("chi-square statistic %.2f exceeds critical value %.2f, distribution looks biased", stat, critical)
+	}
+}
@@ -0,0 +1,68 @@
+package rndstring
+
+import (
+	_ "embed"
+	"errors"
+	"math"
+	"strings"
+)
+
+//go:embed wordlist.txt
+var dicewareWordlistRaw string
+
+var dicewareWordlist = strings.Fields(dicewareWordlistRaw)
+
+// NewPassphraseGenerator returns a StringGenerator producing
+// Diceware-style passphrases: wordCount words drawn uniformly at random
+// from wordlist and joined with sep.
+func NewPassphraseGenerator(wordCount int, sep string, wordlist []string) (StringGenerator, error) {
+	if wordCount < 0 {
+		return nil, errors.New("rndstring: wordCount must not be negative")
+	}
+
+	if len(wordlist) == 0 {
+		return nil, errors.New("rndstring: wordlist must not be empty")
+	}
+
+	list := make([]string, len(wordlist))
+	copy(list, wordlist)
+
+	return entropyGenerator{
+		generator: func() string {
+			words := make([]string, wordCount)
+
+			for i := range words {
+				words[i] = list[uniformIndex(len(list))]
+			}
+
+			return strings.Join(words, sep)
+		},
+		bits: PassphraseEntropyBits(wordCount, len(list)),
+	}, nil
+}
+
+// NewDicewareGenerator is NewPassphraseGenerator backed by an embedded
+// EFF-style long wordlist (7776 words), joined with a single space.
+func NewDicewareGenerator(wordCount int) (StringGenerator, error) {
+	return NewPassphraseGenerator(wordCount, " ", dicewareWordlist)
+}
+
+// newDicewareGenerator adapts NewDicewareGenerator to NewStringGeneratorF
+// so it can be registered under "passphrase". N is interpreted as the
+// number of words, not a byte or string length; see NewStringGenerator.
+// Per the NewStringGeneratorF contract, a negative N yields an empty
+// string rather than an error.
+func newDicewareGenerator(N int) (StringGenerator, error) {
+	if N < 0 {
+		N = 0
+	}
+
+	return NewDicewareGenerator(N)
+}
+
+// PassphraseEntropyBits returns the Shannon entropy, in bits, of a
+// passphrase of wordCount words drawn uniformly from a wordlist of
+// listSize words.
+func PassphraseEntropyBits(wordCount, listSize int) float64 {
+	return float64(wordCount) * math.Log2(float64(listSize))
+}
@@ -0,0 +1,59 @@
+package rndstring
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDicewareGeneratorWordCount(t *testing.T) {
+	g, err := NewDicewareGenerator(6)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	s := g.Generate()
+	words := strings.Split(s, " ")
+
+	if len(words) != 6 {
+		t.Errorf("expected 6 words, got %d (%q)", len(words), s)
+	}
+}
+
+func TestPassphraseGeneratorCustomWordlist(t *testing.T) {
+	list := []string{"alpha", "bravo", "charlie", "delta"}
+
+	g, err := NewPassphraseGenerator(3, "-", list)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	s := g.Generate()
+	words := strings.Split(s, "-")
+
+	if len(words) != 3 {
+		t.Fatalf("expected 3 words, got %d (%q)", len(words), s)
+	}
+
+	for _, w := range words {
+		found := false
+		for _, l := range list {
+			if w == l {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("word %q not from wordlist", w)
+		}
+	}
+}
+
+func TestPassphraseEntropyBits(t *testing.T) {
+	got := PassphraseEntropyBits(6, 7776)
+	want := 6 * 12.92481250360578 // log2(7776)
+
+	if diff := got - want; diff > 1e-6 || diff < -1e-6 {
+		t.Errorf("expected ~%f bits, got %f", want, got)
+	}
+}
@@ -0,0 +1,241 @@
+package rndstring
+
+import "errors"
+
+// ErrExceedsAvailable is returned by NewPolicyGenerator when a Policy
+// cannot be satisfied, e.g. when its minimum class counts add up to more
+// than Length, or, with AllowRepeat false, a class demands more
+// characters than its (deduplicated) alphabet provides.
+var ErrExceedsAvailable = errors.New("rndstring: policy exceeds available characters")
+
+// Policy describes a password composition requirement such as
+// "at least 2 digits, 2 symbols, 1 uppercase, no repeated characters".
+type Policy struct {
+	// Length is the total length of the generated password.
+	Length int
+
+	// MinDigits, MinSymbols, MinUpper and MinLower are the minimum
+	// number of characters from each class that must appear in the
+	// generated password.
+	MinDigits  int
+	MinSymbols int
+	MinUpper   int
+	MinLower   int
+
+	// AllowRepeat controls whether a character may appear more than
+	// once in the generated password.
+	AllowRepeat bool
+
+	// ExcludeAmbiguous drops easily confused characters (0O1lI|) from
+	// every alphabet before generating.
+	ExcludeAmbiguous bool
+
+	// ExtraAlphabet is folded into the union alphabet used to fill any
+	// characters left over once the minimums are met. It does not
+	// count towards MinDigits, MinSymbols, MinUpper or MinLower.
+	ExtraAlphabet []rune
+}
+
+var ambiguousRunes = []rune("0O1lI|")
+
+func isAmbiguous(r rune) bool {
+	for _, a := range ambiguousRunes {
+		if r == a {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dedupRunes converts alphabet to runes, optionally dropping ambiguous
+// ones, and removes duplicates while preserving order.
+func dedupRunes(alphabet []byte, excludeAmbiguous bool) []rune {
+	seen := make(map[rune]bool, len(alphabet))
+	out := make([]rune, 0, len(alphabet))
+
+	for _, b := range alphabet {
+		r := rune(b)
+
+		if excludeAmbiguous && isAmbiguous(r) {
+			continue
+		}
+
+		if seen[r] {
+			continue
+		}
+
+		seen[r] = true
+		out = append(out, r)
+	}
+
+	return out
+}
+
+func dedupRuneSlice(rs []rune) []rune {
+	seen := make(map[rune]bool, len(rs))
+	out := make([]rune, 0, len(rs))
+
+	for _, r := range rs {
+		if seen[r] {
+			continue
+		}
+
+		seen[r] = true
+		out = append(out, r)
+	}
+
+	return out
+}
+
+// policyClass pairs a minimum count with the (already deduplicated)
+// alphabet it is drawn from.
+type policyClass struct {
+	min      int
+	alphabet []rune
+}
+
+// NewPolicyGenerator returns a StringGenerator producing passwords that
+// satisfy p. It returns ErrExceedsAvailable if the minimums cannot be
+// met.
+func NewPolicyGenerator(p Policy) (StringGenerator, error) {
+	classes := []policyClass{
+		{p.MinDigits, dedupRunes(digitsAlphabet, p.ExcludeAmbiguous)},
+		{p.MinSymbols, dedupRunes(symbolsAlphabet, p.ExcludeAmbiguous)},
+		{p.MinUpper, dedupRunes(upperCaseAlphabet, p.ExcludeAmbiguous)},
+		{p.MinLower, dedupRunes(lowerCaseAlphabet, p.ExcludeAmbiguous)},
+	}
+
+	minTotal := p.MinDigits + p.MinSymbols + p.MinUpper + p.MinLower
+
+	if minTotal > p.Length {
+		return nil, ErrExceedsAvailable
+	}
+
+	if !p.AllowRepeat {
+		for _, c := range classes {
+			if c.min > len(c.alphabet) {
+				return nil, ErrExceedsAvailable
+			}
+		}
+	}
+
+	union := make([]rune, 0, len(digitsAlphabet)+len(symbolsAlphabet)+len(upperCaseAlphabet)+len(lowerCaseAlphabet)+len(p.ExtraAlphabet))
+	for _, c := range classes {
+		union = append(union, c.alphabet...)
+	}
+	for _, r := range p.ExtraAlphabet {
+		if p.ExcludeAmbiguous && isAmbiguous(r) {
+			continue
+		}
+
+		union = append(union, r)
+	}
+	union = dedupRuneSlice(union)
+
+	if !p.AllowRepeat && p.Length > len(union) {
+		return nil, ErrExceedsAvailable
+	}
+
+	return generator(func() string {
+		return generatePolicyPassword(p, classes, union)
+	}), nil
+}
+
+func generatePolicyPassword(p Policy, classes []policyClass, union []rune) string {
+	result := make([]rune, 0, p.Length)
+	working := union
+
+	if !p.AllowRepeat {
+		working = append([]rune{}, union...)
+	}
+
+	for _, c := range classes {
+		alphabet := c.alphabet
+
+		if !p.AllowRepeat {
+			alphabet = append([]rune{}, alphabet...)
+		}
+
+		for i := 0; i < c.min; i++ {
+			idx := uniformIndex(len(alphabet))
+			r := alphabet[idx]
+			result = append(result, r)
+
+			if !p.AllowRepeat {
+				alphabet = removeRuneAt(alphabet, idx)
+				working = removeRuneValue(working, r)
+			}
+		}
+	}
+
+	for len(result) < p.Length {
+		idx := uniformIndex(len(working))
+		r := working[idx]
+		result = append(result, r)
+
+		if !p.AllowRepeat {
+			working = removeRuneAt(working, idx)
+		}
+	}
+
+	shuffleRunes(result)
+
+	return string(result)
+}
+
+// removeRuneAt removes the rune at idx from rs in O(1) by swapping in the
+// last element; the caller doesn't care about order.
+func removeRuneAt(rs []rune, idx int) []rune {
+	rs[idx] = rs[len(rs)-1]
+	return rs[:len(rs)-1]
+}
+
+func removeRuneValue(rs []rune, r rune) []rune {
+	for i, v := range rs {
+		if v == r {
+			return removeRuneAt(rs, i)
+		}
+	}
+
+	return rs
+}
+
+// shuffleRunes performs an unbiased in-place Fisher-Yates shuffle.
+func shuffleRunes(rs []rune) {
+	for i := len(rs) - 1; i > 0; i-- {
+		j := uniformIndex(i + 1)
+		rs[i], rs[j] = rs[j], rs[i]
+	}
+}
+
+// newPolicyGenerator adapts NewPolicyGenerator to NewStringGeneratorF so
+// it can be registered under "policy". Per that contract, a negative N
+// yields an empty string rather than ErrExceedsAvailable.
+func newPolicyGenerator(N int) (StringGenerator, error) {
+	if N < 0 {
+		N = 0
+	}
+
+	return NewPolicyGenerator(Policy{Length: N})
+}
+
+// RandomPolicyPassword returns a random password of the given length
+// containing at least `digits` digits and `symbols` symbols, the rest
+// drawn from letters, digits and symbols alike. It returns an empty
+// string if the policy cannot
+// be satisfied; use NewPolicyGenerator directly if you need the error.
+func RandomPolicyPassword(length, digits, symbols int, allowRepeat bool) string {
+	g, err := NewPolicyGenerator(Policy{
+		Length:      length,
+		MinDigits:   digits,
+		MinSymbols:  symbols,
+		AllowRepeat: allowRepeat,
+	})
+
+	if err != nil {
+		return ""
+	}
+
+	return g.Generate()
+}
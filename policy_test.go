@@ -0,0 +1,97 @@
+package rndstring
+
+import (
+	"strings"
+	"testing"
+)
+
+func countInSet(s string, set []byte) int {
+	n := 0
+
+	for _, r := range s {
+		if strings.ContainsRune(string(set), r) {
+			n++
+		}
+	}
+
+	return n
+}
+
+func TestPolicyGeneratorMinimums(t *testing.T) {
+	g, err := NewPolicyGenerator(Policy{
+		Length:      10,
+		MinDigits:   2,
+		MinSymbols:  2,
+		MinUpper:    1,
+		AllowRepeat: true,
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		s := g.Generate()
+
+		if len(s) != 10 {
+			t.Fatalf("expected length 10, got %d (%q)", len(s), s)
+		}
+
+		if countInSet(s, digitsAlphabet) < 2 {
+			t.Errorf("expected at least 2 digits in %q", s)
+		}
+
+		if countInSet(s, symbolsAlphabet) < 2 {
+			t.Errorf("expected at least 2 symbols in %q", s)
+		}
+
+		if countInSet(s, upperCaseAlphabet) < 1 {
+			t.Errorf("expected at least 1 upper case letter in %q", s)
+		}
+	}
+}
+
+func TestPolicyGeneratorNoRepeat(t *testing.T) {
+	g, err := NewPolicyGenerator(Policy{
+		Length:      8,
+		MinDigits:   2,
+		AllowRepeat: false,
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		s := g.Generate()
+		seen := make(map[rune]bool, len(s))
+
+		for _, r := range s {
+			if seen[r] {
+				t.Fatalf("repeated character %q in %q", r, s)
+			}
+			seen[r] = true
+		}
+	}
+}
+
+func TestPolicyGeneratorExceedsAvailable(t *testing.T) {
+	_, err := NewPolicyGenerator(Policy{
+		Length:    3,
+		MinDigits: 5,
+	})
+
+	if err != ErrExceedsAvailable {
+		t.Errorf("expected ErrExceedsAvailable, got %v", err)
+	}
+
+	_, err = NewPolicyGenerator(Policy{
+		Length:      300,
+		MinDigits:   5,
+		AllowRepeat: false,
+	})
+
+	if err != ErrExceedsAvailable {
+		t.Errorf("expected ErrExceedsAvailable, got %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package rndstring
+
+import "strings"
+
+// vowelUnits and consonantUnits are the syllable-sized building blocks
+// used by NewPronounceableGenerator, in the spirit of the classic APG
+// "pronounceable" mode (FIPS-181 / Koremutake-style). Each entry is one
+// or two letters.
+var vowelUnits = []string{
+	"a", "e", "i", "o", "u",
+	"ae", "ah", "ai", "ee", "ei", "ie", "oh", "oo",
+}
+
+var consonantUnits = []string{
+	"b", "c", "d", "f", "g", "h", "j", "k", "l", "m", "n", "p", "r", "s", "t", "v", "w", "x", "y", "z",
+	"ch", "gh", "ng", "ph", "qu", "sh", "th",
+}
+
+// NewPronounceableGenerator returns a StringGenerator producing
+// easier-to-remember tokens of length N by alternating consonant and
+// vowel units instead of picking uniformly random letters. No more than
+// two vowel-units or two consonant-units ever appear in a row.
+func NewPronounceableGenerator(N int) (StringGenerator, error) {
+	return generator(func() string {
+		return joinUnits(pronounceableUnits(N), "", N)
+	}), nil
+}
+
+// NewPronounceableGeneratorWithSep is like NewPronounceableGenerator but
+// inserts sep between the generated syllables. Like
+// NewPronounceableGenerator, the returned string is exactly N characters
+// (including any separators), truncated/padded as needed.
+func NewPronounceableGeneratorWithSep(N int, sep string) (StringGenerator, error) {
+	return generator(func() string {
+		return joinUnits(pronounceableUnits(N), sep, N)
+	}), nil
+}
+
+// pronounceableUnits returns a sequence of vowel/consonant units whose
+// concatenated length is at least N (or no units at all if N <= 0).
+func pronounceableUnits(N int) []string {
+	if N <= 0 {
+		return nil
+	}
+
+	var units []string
+	vowelStreak, consonantStreak := 0, 0
+	prevLen := 0
+	total := 0
+
+	for total < N {
+		useVowel := nextUnitIsVowel(vowelStreak, consonantStreak)
+
+		table := consonantUnits
+		if useVowel {
+			table = vowelUnits
+		}
+
+		unit := pickUnit(table, prevLen == 2)
+		units = append(units, unit)
+		total += len(unit)
+		prevLen = len(unit)
+
+		if useVowel {
+			vowelStreak++
+			consonantStreak = 0
+		} else {
+			consonantStreak++
+			vowelStreak = 0
+		}
+	}
+
+	return units
+}
+
+// nextUnitIsVowel decides the class of the next unit: a fair coin flip,
+// unless a streak of two same-class units forces a switch.
+func nextUnitIsVowel(vowelStreak, consonantStreak int) bool {
+	if vowelStreak >= 2 {
+		return false
+	}
+
+	if consonantStreak >= 2 {
+		return true
+	}
+
+	return uniformIndex(2) == 0
+}
+
+// pickUnit picks a unit from table using unbiased sampling. If
+// oneLetterOnly is set (the previous unit was 2 letters), only 1-letter
+// units are considered to keep the syllable structure regular.
+func pickUnit(table []string, oneLetterOnly bool) string {
+	if !oneLetterOnly {
+		return table[uniformIndex(len(table))]
+	}
+
+	oneLetter := make([]string, 0, len(table))
+	for _, u := range table {
+		if len(u) == 1 {
+			oneLetter = append(oneLetter, u)
+		}
+	}
+
+	return oneLetter[uniformIndex(len(oneLetter))]
+}
+
+// joinUnits concatenates units with sep and truncates/pads the result to
+// exactly N characters.
+func joinUnits(units []string, sep string, N int) string {
+	if N <= 0 {
+		return ""
+	}
+
+	s := strings.Join(units, sep)
+
+	for len(s) < N {
+		s += "a"
+	}
+
+	return s[:N]
+}
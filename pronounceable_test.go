@@ -0,0 +1,76 @@
+package rndstring
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPronounceableGeneratorLength(t *testing.T) {
+	g, err := NewPronounceableGenerator(12)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		s := g.Generate()
+
+		if len(s) != 12 {
+			t.Errorf("expected length 12, got %d (%q)", len(s), s)
+		}
+	}
+}
+
+func TestPronounceableGeneratorNoTripleRun(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		units := pronounceableUnits(40)
+
+		vowelStreak, consonantStreak := 0, 0
+
+		for _, u := range units {
+			if isVowelUnit(u) {
+				vowelStreak++
+				consonantStreak = 0
+			} else {
+				consonantStreak++
+				vowelStreak = 0
+			}
+
+			if vowelStreak >= 3 {
+				t.Fatalf("three vowel-units in a row: %v", units)
+			}
+
+			if consonantStreak >= 3 {
+				t.Fatalf("three consonant-units in a row: %v", units)
+			}
+		}
+	}
+}
+
+func isVowelUnit(u string) bool {
+	for _, v := range vowelUnits {
+		if v == u {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestPronounceableGeneratorWithSep(t *testing.T) {
+	g, err := NewPronounceableGeneratorWithSep(10, "-")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	s := g.Generate()
+
+	if len(s) != 10 {
+		t.Errorf("expected length 10, got %d (%q)", len(s), s)
+	}
+
+	if !strings.Contains(s, "-") {
+		t.Errorf("expected separators in %q", s)
+	}
+}